@@ -13,7 +13,50 @@
 // 4. Configuration file values.
 // 5. Default values.
 //
-// NOTE: Viper key/value store and/or watching config sources is not yet supported.
+// Config also supports watching its configuration file for changes via
+// Config.Watch, which re-populates your struct and invokes a callback
+// whenever the file changes on disk.
+//
+// Beyond string/bool/float/int/uint scalars, fields may also be nested
+// structs (tagged recursively, e.g. DB.Host), []string, []int,
+// map[string]string, time.Duration, and time.Time. Nested struct fields are
+// addressed using a dotted path, and slice/map defaults may be supplied as
+// comma-separated values, e.g. `default:"a,b,c"`.
+//
+// Fields may also carry a `validate` tag (e.g.
+// `validate:"required,min=1,max=65535,oneof=dev staging prod"`) which
+// Config.Load checks once the struct is populated, aggregating every
+// failure into a *ValidationError rather than stopping at the first one.
+// If your struct implements the Validator interface, Load calls Validate()
+// afterwards for any additional checks the tags can't express.
+//
+// Load parses os.Args[1:] against every bound flag, so flags work the way
+// callers of a CLI tool expect: `myapp --port=8080` and `myapp --help`/`-h`
+// both take effect without the caller doing anything extra. Arguments
+// configurator doesn't recognize (e.g. another package's own flags) are
+// ignored rather than rejected. Config.ErrorHandling (aliased from
+// pflag.ErrorHandling) controls what happens on a bad flag value or a
+// --help/-h request: ContinueOnError returns an error, ExitOnError calls
+// os.Exit, and PanicOnError panics. Every bound flag can also carry a
+// `description` tag, which together with its env var name, file key, and
+// default is printed by Config.PrintDefaults -- the same information shown
+// for an automatic --help/-h request.
+//
+// Config.EnvPrefix, Config.EnvKeyReplacer, and Config.AutomaticEnv control
+// environment variable lookups the same way their Viper counterparts do.
+// With AutomaticEnv on, a field doesn't need an explicit env tag at all --
+// it resolves from strings.ToUpper(EnvPrefix + "_" + path) (just
+// strings.ToUpper(path) when EnvPrefix is empty), with EnvKeyReplacer then
+// applied to that whole name, e.g. to turn the dots of a nested path into
+// underscores.
+//
+// After Load, Config.Source and Config.Sources report which of the five
+// precedence levels actually supplied each field's final value. This is
+// useful for startup introspection -- logging, or serving, the effective
+// configuration and where each value came from, the same way Mattermost's
+// environment-config endpoint does.
+//
+// NOTE: Viper key/value store is not yet supported.
 //
 // Use of this source code is governed by an MIT-style
 // license that can be found in the LICENSE file.
@@ -23,18 +66,23 @@ import (
 	"errors"
 	"fmt"
 	"go/ast"
+	"os"
 	"reflect"
 	"strconv"
+	"strings"
+	"sync"
 
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 )
 
 const (
-	tagEnv     = "env"
-	tagFlag    = "flag"
-	tagFile    = "file"
-	tagDefault = "default"
+	tagEnv         = "env"
+	tagFlag        = "flag"
+	tagFile        = "file"
+	tagDefault     = "default"
+	tagValidate    = "validate"
+	tagDescription = "description"
 )
 
 var (
@@ -107,8 +155,65 @@ type Config struct {
 	// FilePaths is an array of configuration file paths to search for the configuration file.
 	FilePaths []string
 
+	// FileDecoders is the set of decoders used to find and decode FileName
+	// across FilePaths. If left unset, configurator registers JSON, YAML,
+	// TOML, and dotenv decoders by default. Register your own FileDecoder
+	// to support additional file formats without forking configurator.
+	FileDecoders []FileDecoder
+
+	// ErrorHandling controls how a bad flag value or a --help/-h request
+	// (both parsed from the real os.Args) are handled. Defaults to
+	// ContinueOnError, the pflag zero value.
+	ErrorHandling ErrorHandling
+
+	// EnvPrefix is prepended, joined with an underscore, to the environment
+	// variable names AutomaticEnv derives from field paths. A name given
+	// explicitly in an env tag is used as written, without the prefix.
+	EnvPrefix string
+
+	// EnvKeyReplacer rewrites a field's dotted path before it's matched
+	// against an environment variable, e.g. strings.NewReplacer(".", "_")
+	// so a nested field DB.Host matches DB_HOST.
+	EnvKeyReplacer *strings.Replacer
+
+	// AutomaticEnv, when true, makes every field -- even one without an
+	// explicit env tag -- resolvable from strings.ToUpper(EnvPrefix + "_" +
+	// path), with EnvKeyReplacer then applied to that name. This matches Viper's own
+	// AutomaticEnv ergonomics and removes the need to tag every field once
+	// nested structs are in play.
+	AutomaticEnv bool
+
+	// Overrides lists the dotted paths of fields the caller is deliberately
+	// setting to their zero value before Load, e.g. Overrides:
+	// []string{"Port"} for a Port field pre-set to 0 to mean "disabled".
+	// Load already detects a non-zero pre-set field as an override on its
+	// own; Overrides exists because a zero value is indistinguishable from
+	// a field nothing ever touched, so Config.Source and the `required`
+	// validation tag can't tell them apart without this explicit signal.
+	Overrides []string
+
 	externalConfig *interface{}
 	viper          *viper.Viper
+	internal       *configState
+}
+
+// configState holds Config's mutable bookkeeping behind a pointer so that
+// Config itself stays safely copyable by value -- required since the
+// package's own convention is to embed Config by value in a caller's
+// struct (see the Config doc comment).
+type configState struct {
+	mu      sync.Mutex
+	fields  []fieldMeta
+	sources map[string]Source
+}
+
+// state returns c's lazily-initialized internal state, allocating it on
+// first use so a zero-value Config works without explicit initialization.
+func (c *Config) state() *configState {
+	if c.internal == nil {
+		c.internal = &configState{}
+	}
+	return c.internal
 }
 
 // Load attempts to populate the struct with configuration values.
@@ -142,7 +247,19 @@ func (c *Config) Load(structRef interface{}) error {
 	ptrRef := reflect.ValueOf(structRef)
 	ref := ptrRef.Elem()
 
-	return c.parseStructConfigValues(ref, structRef)
+	if err := c.parseStructConfigValues(ref, structRef); err != nil {
+		return err
+	}
+
+	if err := c.validateStruct(ref); err != nil {
+		return err
+	}
+
+	if validator, ok := structRef.(Validator); ok {
+		return validator.Validate()
+	}
+
+	return nil
 }
 
 func (c *Config) canLoad(structRef interface{}) error {
@@ -173,15 +290,36 @@ func (c *Config) parseStructConfigValues(structRef reflect.Value, val interface{
 	envValues := parseEnvValues(structRef)
 	flagValues := parseFlagValues(structRef)
 	configValues := parseConfigFileValues(structRef)
+	descriptions := parseValuesForTag(structRef, tagDescription)
+
+	c.state().fields = buildFieldMeta(defaultValues, envValues, flagValues, configValues, descriptions)
 
+	overrides := map[string]bool{}
+	snapshotOverrides(structRef, "", overrides)
+	for _, path := range c.Overrides {
+		overrides[path] = true
+	}
+
+	c.applyEnvSettings()
 	c.populateDefaults(defaultValues)
 	c.bindEnvValues(envValues)
-	c.bindFlagValues(flagValues)
+	flagSet, err := c.bindFlagValues(flagValues)
+	if err != nil {
+		return err
+	}
 	c.bindConfigFileValues(configValues)
 
-	err := c.populateConfigStruct(structRef)
+	if err := c.handleHelp(flagSet); err != nil {
+		return err
+	}
+
+	if err := c.populateConfigStruct(structRef); err != nil {
+		return err
+	}
+
+	c.state().sources = c.computeSources(flagSet, defaultValues, envValues, flagValues, configValues, overrides)
 
-	return err
+	return nil
 }
 
 func parseDefaultValues(structRef reflect.Value) map[string]parsedValue {
@@ -206,19 +344,48 @@ func parseConfigFileValues(structRef reflect.Value) map[string]parsedValue {
 
 func parseValuesForTag(structRef reflect.Value, tagName string) map[string]parsedValue {
 	values := map[string]parsedValue{}
+	collectValuesForTag(structRef, "", tagName, values)
+	return values
+}
 
+// collectValuesForTag walks structRef, recursing into nested/embedded
+// structs (other than the embedded Config and time.Time) so that a field
+// like DB.Host is collected under the dotted path "DB.Host".
+func collectValuesForTag(structRef reflect.Value, parentPath, tagName string, values map[string]parsedValue) {
 	structType := structRef.Type()
 	for i := 0; i < structType.NumField(); i++ {
 		structField := structType.Field(i)
-		tag := structField.Tag
-		tagValue := tag.Get(tagName)
+		if structField.Type == configType || !ast.IsExported(structField.Name) {
+			continue
+		}
+
+		path := fieldPath(parentPath, structField.Name)
 
-		if tagValue != "" && ast.IsExported(structField.Name) {
-			values[structField.Name] = parsedValue{tagValue, structField.Type}
+		if tagValue := structField.Tag.Get(tagName); tagValue != "" {
+			values[path] = parsedValue{tagValue, structField.Type}
+		}
+
+		if isNestedStruct(structField.Type) {
+			collectValuesForTag(structRef.Field(i), path, tagName, values)
 		}
 	}
+}
 
-	return values
+// fieldPath joins a parent dotted path and a field name, e.g.
+// fieldPath("DB", "Host") returns "DB.Host". An empty parentPath returns
+// fieldName unchanged, preserving top-level field keys like "Host".
+func fieldPath(parentPath, fieldName string) string {
+	if parentPath == "" {
+		return fieldName
+	}
+	return parentPath + "." + fieldName
+}
+
+// isNestedStruct reports whether t should be recursed into as a nested
+// struct rather than treated as a leaf value. time.Time is a struct but is
+// handled as a leaf by populateStructField.
+func isNestedStruct(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t != timeType
 }
 
 /////////////
@@ -231,27 +398,38 @@ func (c *Config) bindEnvValues(envValues map[string]parsedValue) {
 	}
 }
 
-func (c *Config) bindFlagValues(flagValues map[string]parsedValue) *pflag.FlagSet {
-	flagSet := pflag.NewFlagSet("configurator", pflag.PanicOnError)
+// bindFlagValues registers every tagged flag -- and --help/-h -- on a
+// FlagSet private to this Load call (rather than the package-level
+// pflag.CommandLine), so that one Config's flags never leak into another
+// Load call or another Config, then parses os.Args[1:] against it so a
+// real `myapp --help` (or `myapp --port=8080`) invocation actually takes
+// effect. Unrecognized arguments (e.g. a test binary's own -test.* flags)
+// are ignored rather than rejected, since configurator only owns the
+// flags callers tagged.
+func (c *Config) bindFlagValues(flagValues map[string]parsedValue) (*pflag.FlagSet, error) {
+	flagSet := pflag.NewFlagSet("configurator", c.ErrorHandling)
+	flagSet.ParseErrorsWhitelist.UnknownFlags = true
 
 	for k, v := range flagValues {
-		pflag.String(v.tagValue, "", "")
-		flag := pflag.Lookup(v.tagValue)
-
-		c.viper.BindPFlag(k, flag)
-		flagSet.AddFlag(flag)
+		flagSet.String(v.tagValue, "", "")
+		c.viper.BindPFlag(k, flagSet.Lookup(v.tagValue))
 	}
 
-	return flagSet
-}
+	flagSet.BoolP("help", "h", false, "display this help text")
 
-func (c *Config) bindConfigFileValues(configValues map[string]parsedValue) {
-	c.viper.SetConfigName(c.FileName)
+	var args []string
+	if len(os.Args) > 1 {
+		args = os.Args[1:]
+	}
 
-	for _, filePath := range c.FilePaths {
-		c.viper.AddConfigPath(filePath)
+	if err := flagSet.Parse(args); err != nil {
+		return flagSet, err
 	}
 
+	return flagSet, nil
+}
+
+func (c *Config) bindConfigFileValues(configValues map[string]parsedValue) {
 	// Map the config file keys to our variable
 	for k, v := range configValues {
 		c.viper.RegisterAlias(k, v.tagValue)
@@ -269,32 +447,77 @@ func (c *Config) populateDefaults(defaultValues map[string]parsedValue) {
 }
 
 func (c *Config) populateConfigStruct(structRef reflect.Value) error {
-	c.viper.ReadInConfig()
+	if err := c.loadConfigFiles(); err != nil {
+		return err
+	}
+
+	return c.populateStructFields(structRef, "")
+}
 
+// populateStructFields walks structRef, recursing into nested/embedded
+// structs under their dotted path (see collectValuesForTag) and populating
+// each leaf field from the internal Viper instance.
+func (c *Config) populateStructFields(structRef reflect.Value, parentPath string) error {
 	structType := structRef.Type()
 	for i := 0; i < structType.NumField(); i++ {
 		structField := structType.Field(i)
-		configValue := c.viper.Get(structField.Name)
-		if configValue != nil {
-			err := populateStructField(structField, structRef.Field(i), fmt.Sprintf("%v", configValue))
+		if structField.Type == configType {
+			continue
+		}
 
-			if err != nil {
+		fieldValue := structRef.Field(i)
+		path := fieldPath(parentPath, structField.Name)
+
+		if isNestedStruct(structField.Type) {
+			if err := c.populateStructFields(fieldValue, path); err != nil {
 				return err
 			}
+			continue
+		}
+
+		// A path listed in Overrides keeps its pre-set (zero) value, the
+		// same way a non-zero pre-set field is never overwritten.
+		listedOverride := false
+		for _, overridePath := range c.Overrides {
+			if overridePath == path {
+				listedOverride = true
+				break
+			}
+		}
+		if listedOverride {
+			continue
+		}
+
+		configValue := c.viper.Get(path)
+		if configValue == nil {
+			continue
+		}
+
+		if err := populateStructField(structField, fieldValue, configValue); err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
-func populateStructField(field reflect.StructField, fieldValue reflect.Value, value string) error {
+func populateStructField(field reflect.StructField, fieldValue reflect.Value, configValue interface{}) error {
+	if field.Type == durationType {
+		return populateDuration(field, fieldValue, configValue)
+	}
+	if field.Type == timeType {
+		return populateTime(field, fieldValue, configValue)
+	}
+
 	switch fieldValue.Kind() {
 	case reflect.String:
+		value := fmt.Sprintf("%v", configValue)
 		if isZeroOfUnderlyingType(fieldValue.Interface()) {
 			fieldValue.SetString(value)
 		}
 
 	case reflect.Bool:
+		value := fmt.Sprintf("%v", configValue)
 		bvalue, err := strconv.ParseBool(value)
 		if err != nil {
 			return fmt.Errorf("Unable to convert value (%s) for to bool for field: %s! Error: %s", value, field.Name, err.Error())
@@ -305,6 +528,7 @@ func populateStructField(field reflect.StructField, fieldValue reflect.Value, va
 		}
 
 	case reflect.Float32, reflect.Float64:
+		value := fmt.Sprintf("%v", configValue)
 		floatValue, err := strconv.ParseFloat(value, 64)
 		if err != nil {
 			return fmt.Errorf("Unable to convert value (%s) for to float for field: %s! Error: %s", value, field.Name, err.Error())
@@ -315,6 +539,7 @@ func populateStructField(field reflect.StructField, fieldValue reflect.Value, va
 		}
 
 	case reflect.Int, reflect.Int8, reflect.Int32, reflect.Int64:
+		value := fmt.Sprintf("%v", configValue)
 		intValue, err := strconv.ParseInt(value, 10, 64)
 		if err != nil {
 			return fmt.Errorf("Unable to convert value (%s) for to int for field: %s! Error: %s", value, field.Name, err.Error())
@@ -324,6 +549,7 @@ func populateStructField(field reflect.StructField, fieldValue reflect.Value, va
 			fieldValue.SetInt(intValue)
 		}
 	case reflect.Uint, reflect.Uint8, reflect.Uint32, reflect.Uint64:
+		value := fmt.Sprintf("%v", configValue)
 		intValue, err := strconv.ParseUint(value, 10, 64)
 		if err != nil {
 			return fmt.Errorf("Unable to convert value (%s) for to unsigned int for field: %s! Error: %s", value, field.Name, err.Error())
@@ -332,6 +558,12 @@ func populateStructField(field reflect.StructField, fieldValue reflect.Value, va
 		if isZeroOfUnderlyingType(fieldValue.Interface()) {
 			fieldValue.SetUint(intValue)
 		}
+
+	case reflect.Slice:
+		return populateSlice(field, fieldValue, configValue)
+
+	case reflect.Map:
+		return populateMap(field, fieldValue, configValue)
 	}
 	return nil
 }
@@ -3,8 +3,11 @@ package configurator
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
@@ -175,7 +178,8 @@ func TestLoadConfigFromFlagsSuccess(t *testing.T) {
 	ptrRef := reflect.ValueOf(&config)
 	structRef := ptrRef.Elem()
 	flagValues := parseFlagValues(structRef)
-	flagSet := config.bindFlagValues(flagValues)
+	flagSet, err := config.bindFlagValues(flagValues)
+	assert.Nil(t, err)
 
 	fmt.Println("flag vals", flagValues)
 
@@ -246,7 +250,8 @@ func TestLoadConfigFromFlagsFailureBadValues(t *testing.T) {
 	ptrRef := reflect.ValueOf(&config)
 	structRef := ptrRef.Elem()
 	flagValues := parseFlagValues(structRef)
-	flagSet := config.bindFlagValues(flagValues)
+	flagSet, err := config.bindFlagValues(flagValues)
+	assert.Nil(t, err)
 
 	var expectedFlagValues = map[string]string{
 		"env2":      "dev",
@@ -264,7 +269,7 @@ func TestLoadConfigFromFlagsFailureBadValues(t *testing.T) {
 
 	defaultValues := parseDefaultValues(structRef)
 	config.populateDefaults(defaultValues)
-	err := config.populateConfigStruct(structRef)
+	err = config.populateConfigStruct(structRef)
 
 	assert.NotNil(t, err)
 }
@@ -361,6 +366,252 @@ func TestLoadConfigFromFileFailureBadValue(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
+func TestLoadNestedStructSlicesMapsAndDuration(t *testing.T) {
+	type dbConfig struct {
+		Host string `env:"APP_DB_HOST" default:"localhost"`
+		Port int    `env:"APP_DB_PORT" default:"5432"`
+	}
+
+	type testNestedConfig struct {
+		Config
+
+		DB      dbConfig
+		Tags    []string          `default:"a,b,c"`
+		Limits  []int             `default:"1,2,3"`
+		Labels  map[string]string `default:"env=dev,team=core"`
+		Timeout time.Duration     `default:"5s"`
+	}
+
+	config := testNestedConfig{}
+	os.Clearenv()
+
+	err := config.Load(&config)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "localhost", config.DB.Host)
+	assert.Equal(t, 5432, config.DB.Port)
+	assert.Equal(t, []string{"a", "b", "c"}, config.Tags)
+	assert.Equal(t, []int{1, 2, 3}, config.Limits)
+	assert.Equal(t, "dev", config.Labels["env"])
+	assert.Equal(t, "core", config.Labels["team"])
+	assert.Equal(t, 5*time.Second, config.Timeout)
+}
+
+func TestLoadNestedStructFromEnvOverridesDefault(t *testing.T) {
+	type dbConfig struct {
+		Host string `env:"APP_DB_HOST" default:"localhost"`
+	}
+
+	type testNestedConfig struct {
+		Config
+
+		DB dbConfig
+	}
+
+	config := testNestedConfig{}
+	os.Clearenv()
+	os.Setenv("APP_DB_HOST", "db.internal")
+
+	err := config.Load(&config)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "db.internal", config.DB.Host)
+}
+
+func TestValidateRequiredFailsWhenUnset(t *testing.T) {
+	type testValidatedConfig struct {
+		Config
+		Port int `env:"APP_PORT" validate:"required"`
+	}
+
+	config := testValidatedConfig{}
+	os.Clearenv()
+
+	err := config.Load(&config)
+	assert.NotNil(t, err)
+	assert.IsType(t, &ValidationError{}, err)
+}
+
+func TestValidateRequiredPassesWithZeroFromEnv(t *testing.T) {
+	type testValidatedConfig struct {
+		Config
+		Port int `env:"APP_PORT" validate:"required"`
+	}
+
+	config := testValidatedConfig{}
+	os.Clearenv()
+	os.Setenv("APP_PORT", "0")
+
+	err := config.Load(&config)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, config.Port)
+}
+
+func TestValidateRequiredPassesWithStructOverrideOnly(t *testing.T) {
+	type testValidatedConfig struct {
+		Config
+		Port int `validate:"required"`
+	}
+
+	config := testValidatedConfig{Port: 8080}
+	os.Clearenv()
+
+	err := config.Load(&config)
+	assert.Nil(t, err)
+	assert.Equal(t, 8080, config.Port)
+}
+
+func TestValidateRequiredPassesWithZeroOverrideOptIn(t *testing.T) {
+	type testValidatedConfig struct {
+		Config
+		Port int `validate:"required"`
+	}
+
+	config := testValidatedConfig{Port: 0}
+	config.Overrides = []string{"Port"}
+	os.Clearenv()
+
+	err := config.Load(&config)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, config.Port)
+	assert.Equal(t, SourceOverride, config.Source("Port"))
+}
+
+func TestValidateMinMaxAndOneof(t *testing.T) {
+	type testValidatedConfig struct {
+		Config
+		Port        int    `env:"APP_PORT" default:"70000" validate:"min=1,max=65535"`
+		Environment string `env:"APP_ENV" default:"qa" validate:"oneof=dev staging prod"`
+	}
+
+	config := testValidatedConfig{}
+	os.Clearenv()
+
+	err := config.Load(&config)
+	assert.NotNil(t, err)
+
+	validationErr, ok := err.(*ValidationError)
+	assert.True(t, ok)
+	assert.Equal(t, 2, len(validationErr.Errors))
+}
+
+func TestValidatorInterfaceCalledAfterPopulation(t *testing.T) {
+	config := &testValidatorConfig{}
+	os.Clearenv()
+
+	err := config.Load(config)
+	assert.NotNil(t, err)
+	assert.Equal(t, "environment must not be dev", err.Error())
+}
+
+type testValidatorConfig struct {
+	Config
+	Environment string `env:"APP_ENV" default:"dev"`
+}
+
+func (c *testValidatorConfig) Validate() error {
+	if c.Environment == "dev" {
+		return fmt.Errorf("environment must not be dev")
+	}
+	return nil
+}
+
+func TestHandleHelpReturnsErrHelpRequestedOnContinueOnError(t *testing.T) {
+	type testHelpConfig struct {
+		Config
+		Port int `flag:"port6" description:"the port to listen on" default:"3306"`
+	}
+
+	config := testHelpConfig{}
+	config.viper = viper.New()
+	os.Clearenv()
+
+	ptrRef := reflect.ValueOf(&config)
+	structRef := ptrRef.Elem()
+	flagValues := parseFlagValues(structRef)
+	flagSet, err := config.bindFlagValues(flagValues)
+	assert.Nil(t, err)
+
+	flagSet.VisitAll(func(flag *pflag.Flag) {
+		if flag.Name == "help" {
+			flag.Value.Set("true")
+			flag.Changed = true
+		}
+	})
+
+	defaultValues := parseDefaultValues(structRef)
+	config.state().fields = buildFieldMeta(defaultValues, map[string]parsedValue{}, flagValues, map[string]parsedValue{}, map[string]parsedValue{})
+
+	err = config.handleHelp(flagSet)
+	assert.Equal(t, ErrHelpRequested, err)
+}
+
+func TestHandleHelpNoOpWhenNotRequested(t *testing.T) {
+	type testHelpConfig struct {
+		Config
+		Port int `flag:"port7" default:"3306"`
+	}
+
+	config := testHelpConfig{}
+	config.viper = viper.New()
+	os.Clearenv()
+
+	ptrRef := reflect.ValueOf(&config)
+	structRef := ptrRef.Elem()
+	flagValues := parseFlagValues(structRef)
+	flagSet, err := config.bindFlagValues(flagValues)
+	assert.Nil(t, err)
+
+	err = config.handleHelp(flagSet)
+	assert.Nil(t, err)
+}
+
+func TestAutomaticEnvResolvesUntaggedFieldsFromPrefix(t *testing.T) {
+	type testAutomaticEnvConfig struct {
+		Config
+		Host string
+		Port int
+	}
+
+	config := testAutomaticEnvConfig{}
+	config.AutomaticEnv = true
+	config.EnvPrefix = "APP"
+
+	os.Clearenv()
+	os.Setenv("APP_HOST", "db.internal")
+	os.Setenv("APP_PORT", "6543")
+
+	err := config.Load(&config)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "db.internal", config.Host)
+	assert.Equal(t, 6543, config.Port)
+}
+
+func TestAutomaticEnvWithReplacerResolvesNestedFields(t *testing.T) {
+	type dbConfig struct {
+		Host string
+	}
+
+	type testAutomaticEnvConfig struct {
+		Config
+		DB dbConfig
+	}
+
+	config := testAutomaticEnvConfig{}
+	config.AutomaticEnv = true
+	config.EnvPrefix = "APP"
+	config.EnvKeyReplacer = strings.NewReplacer(".", "_")
+
+	os.Clearenv()
+	os.Setenv("APP_DB_HOST", "db.internal")
+
+	err := config.Load(&config)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "db.internal", config.DB.Host)
+}
+
 func TestCompoundSourcesScenario(t *testing.T) {
 	type testFileConfig struct {
 		Config
@@ -372,3 +623,257 @@ func TestCompoundSourcesScenario(t *testing.T) {
 		Restricted  bool    `file:"enabled" env:"APP_RESTRICTED" flag:"restricted" default:"false"`
 	}
 }
+
+func TestSourceReportsDefaultWhenNothingElseSet(t *testing.T) {
+	type testSourceConfig struct {
+		Config
+		Port int `env:"APP_SRC_PORT" default:"3306"`
+	}
+
+	config := testSourceConfig{}
+	os.Clearenv()
+
+	err := config.Load(&config)
+	assert.Nil(t, err)
+
+	assert.Equal(t, SourceDefault, config.Source("Port"))
+}
+
+func TestSourceReportsUnsetForUnknownOrUnpopulatedField(t *testing.T) {
+	type testSourceConfig struct {
+		Config
+		Environment string `env:"APP_SRC_ENV"`
+	}
+
+	config := testSourceConfig{}
+	os.Clearenv()
+
+	err := config.Load(&config)
+	assert.Nil(t, err)
+
+	assert.Equal(t, SourceUnset, config.Source("Environment"))
+	assert.Equal(t, SourceUnset, config.Source("DoesNotExist"))
+}
+
+func TestSourceReportsEnvWhenEnvVarSet(t *testing.T) {
+	type testSourceConfig struct {
+		Config
+		Host string `env:"APP_SRC_HOST" default:"localhost"`
+	}
+
+	config := testSourceConfig{}
+	os.Clearenv()
+	os.Setenv("APP_SRC_HOST", "db.internal")
+
+	err := config.Load(&config)
+	assert.Nil(t, err)
+
+	assert.Equal(t, SourceEnv, config.Source("Host"))
+}
+
+func TestSourceReportsOverrideWhenFieldAlreadySet(t *testing.T) {
+	type testSourceConfig struct {
+		Config
+		Port int `env:"APP_SRC_PORT" default:"3306"`
+	}
+
+	config := testSourceConfig{}
+	config.Port = 9000
+	os.Clearenv()
+	os.Setenv("APP_SRC_PORT", "4000")
+
+	err := config.Load(&config)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 9000, config.Port)
+	assert.Equal(t, SourceOverride, config.Source("Port"))
+}
+
+func TestSourceReportsFileWhenOnlyFileHasAValue(t *testing.T) {
+	type testSourceConfig struct {
+		Config
+		Host string `file:"host" default:"localhost"`
+	}
+
+	configData := []byte(`{"host": "127.0.0.1"}`)
+	filePath := "./sourceconfig.json"
+	testConfigFile, err := os.Create(filePath)
+	assert.Nil(t, err)
+
+	defer func() {
+		testConfigFile.Close()
+		os.Remove(filePath)
+	}()
+
+	_, err = testConfigFile.Write(configData)
+	assert.Nil(t, err)
+	testConfigFile.Sync()
+
+	config := testSourceConfig{}
+	config.FileName = "sourceconfig"
+	config.FilePaths = []string{"."}
+	os.Clearenv()
+
+	err = config.Load(&config)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "127.0.0.1", config.Host)
+	assert.Equal(t, SourceFile, config.Source("Host"))
+}
+
+func TestSourceReportsFlagWhenFlagChanged(t *testing.T) {
+	type testSourceConfig struct {
+		Config
+		Port int `flag:"portsrc" default:"3306"`
+	}
+
+	config := testSourceConfig{}
+	config.viper = viper.New()
+	os.Clearenv()
+
+	ptrRef := reflect.ValueOf(&config)
+	structRef := ptrRef.Elem()
+	flagValues := parseFlagValues(structRef)
+	flagSet, err := config.bindFlagValues(flagValues)
+	assert.Nil(t, err)
+
+	flagSet.VisitAll(func(flag *pflag.Flag) {
+		if flag.Name == "portsrc" {
+			flag.Value.Set("4000")
+			flag.Changed = true
+		}
+	})
+
+	overrides := map[string]bool{}
+	snapshotOverrides(structRef, "", overrides)
+
+	defaultValues := parseDefaultValues(structRef)
+	config.populateDefaults(defaultValues)
+	assert.Nil(t, config.populateConfigStruct(structRef))
+
+	config.state().sources = config.computeSources(flagSet, defaultValues, map[string]parsedValue{}, flagValues, map[string]parsedValue{}, overrides)
+
+	assert.Equal(t, 4000, config.Port)
+	assert.Equal(t, SourceFlag, config.Source("Port"))
+}
+
+func TestSourceReportsEnvForAutomaticEnvField(t *testing.T) {
+	type testSourceConfig struct {
+		Config
+		Host string
+	}
+
+	config := testSourceConfig{}
+	config.AutomaticEnv = true
+	config.EnvPrefix = "APP"
+
+	os.Clearenv()
+	os.Setenv("APP_HOST", "db.internal")
+
+	err := config.Load(&config)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "db.internal", config.Host)
+	assert.Equal(t, SourceEnv, config.Source("Host"))
+}
+
+func TestSourcesReturnsEverySourceSeen(t *testing.T) {
+	type testSourceConfig struct {
+		Config
+		Environment string `env:"APP_SRC_ENVIRONMENT" default:"development"`
+		Host        string `env:"APP_SRC_HOST2" default:"localhost"`
+	}
+
+	config := testSourceConfig{}
+	os.Clearenv()
+	os.Setenv("APP_SRC_HOST2", "db.internal")
+
+	err := config.Load(&config)
+	assert.Nil(t, err)
+
+	sources := config.Sources()
+	assert.Equal(t, SourceDefault, sources["Environment"])
+	assert.Equal(t, SourceEnv, sources["Host"])
+}
+
+func TestWatchReloadsStructOnFileChange(t *testing.T) {
+	type testWatchConfig struct {
+		Config
+		Host string `file:"host" default:"localhost"`
+	}
+
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "watchconfig.json")
+	assert.Nil(t, os.WriteFile(filePath, []byte(`{"host": "127.0.0.1"}`), 0644))
+
+	config := testWatchConfig{}
+	config.FileName = "watchconfig"
+	config.FilePaths = []string{dir}
+
+	err := config.Load(&config)
+	assert.Nil(t, err)
+	assert.Equal(t, "127.0.0.1", config.Host)
+
+	changed := make(chan string, 1)
+	stop, err := config.Watch(&config, func(old, new interface{}) error {
+		changed <- new.(testWatchConfig).Host
+		return nil
+	})
+	assert.Nil(t, err)
+	defer stop()
+
+	assert.Nil(t, os.WriteFile(filePath, []byte(`{"host": "10.0.0.1"}`), 0644))
+
+	select {
+	case host := <-changed:
+		assert.Equal(t, "10.0.0.1", host)
+	case <-time.After(3 * time.Second):
+		t.Fatal("onChange callback did not fire before timeout")
+	}
+
+	assert.Equal(t, "10.0.0.1", config.Host)
+}
+
+func TestWatchReloadPreservesAutomaticEnvField(t *testing.T) {
+	type testWatchConfig struct {
+		Config
+		Host string `file:"host" default:"localhost"`
+		Port int
+	}
+
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "watchconfig.json")
+	assert.Nil(t, os.WriteFile(filePath, []byte(`{"host": "127.0.0.1"}`), 0644))
+
+	config := testWatchConfig{}
+	config.FileName = "watchconfig"
+	config.FilePaths = []string{dir}
+	config.AutomaticEnv = true
+	config.EnvPrefix = "APP"
+
+	os.Clearenv()
+	os.Setenv("APP_PORT", "9999")
+
+	err := config.Load(&config)
+	assert.Nil(t, err)
+	assert.Equal(t, 9999, config.Port)
+
+	changed := make(chan int, 1)
+	stop, err := config.Watch(&config, func(old, new interface{}) error {
+		changed <- new.(testWatchConfig).Port
+		return nil
+	})
+	assert.Nil(t, err)
+	defer stop()
+
+	assert.Nil(t, os.WriteFile(filePath, []byte(`{"host": "10.0.0.1"}`), 0644))
+
+	select {
+	case port := <-changed:
+		assert.Equal(t, 9999, port)
+	case <-time.After(3 * time.Second):
+		t.Fatal("onChange callback did not fire before timeout")
+	}
+
+	assert.Equal(t, 9999, config.Port)
+}
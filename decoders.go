@@ -0,0 +1,177 @@
+package configurator
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// FileDecoder decodes a configuration file of a particular format into a
+// map of keys to values, with nested objects as nested maps (so a nested
+// field DB.Host is found under "db" -> "host"). Register custom decoders on
+// Config.FileDecoders to support additional file formats (e.g. HCL or an
+// encrypted store) without forking configurator.
+type FileDecoder interface {
+	// Format returns the file extension (without the leading dot) this
+	// decoder handles, e.g. "yaml" or "toml".
+	Format() string
+
+	// DecodeFile reads and decodes the file at path into a map of keys to
+	// values.
+	DecodeFile(path string) (map[string]interface{}, error)
+}
+
+// defaultFileDecoders returns the decoders configurator registers
+// automatically when Config.FileDecoders is left unset: JSON, YAML, TOML,
+// and dotenv.
+func defaultFileDecoders() []FileDecoder {
+	return []FileDecoder{
+		jsonFileDecoder{},
+		yamlFileDecoder{},
+		tomlFileDecoder{},
+		dotenvFileDecoder{},
+	}
+}
+
+// decoders returns the Config's registered FileDecoders, falling back to
+// defaultFileDecoders when none have been registered.
+func (c *Config) decoders() []FileDecoder {
+	if len(c.FileDecoders) > 0 {
+		return c.FileDecoders
+	}
+
+	return defaultFileDecoders()
+}
+
+// loadConfigFiles finds FileName across FilePaths and decodes every match
+// it finds using the matching registered FileDecoder, merging the results
+// into the internal Viper instance in FilePaths order. A FilePaths entry
+// that contains more than one matching file (e.g. both config.json and
+// config.yaml) merges all of them, in decoder registration order.
+func (c *Config) loadConfigFiles() error {
+	if c.FileName == "" {
+		return nil
+	}
+
+	watchPathSet := false
+
+	for _, dir := range c.FilePaths {
+		for _, decoder := range c.decoders() {
+			path := filepath.Join(dir, c.FileName+"."+decoder.Format())
+			if _, err := os.Stat(path); err != nil {
+				continue
+			}
+
+			values, err := decoder.DecodeFile(path)
+			if err != nil {
+				return fmt.Errorf("Unable to decode config file %s! Error: %s", path, err.Error())
+			}
+
+			if err := c.viper.MergeConfigMap(values); err != nil {
+				return err
+			}
+
+			// SetConfigFile tells Viper which file to watch for changes --
+			// MergeConfigMap alone never does, so without it Config.Watch's
+			// call to viper.WatchConfig has no file to watch and silently
+			// no-ops. The first match found becomes "the" watched file even
+			// when multiple formats merge together.
+			if !watchPathSet {
+				c.viper.SetConfigFile(path)
+				watchPathSet = true
+			}
+		}
+	}
+
+	return nil
+}
+
+type jsonFileDecoder struct{}
+
+func (jsonFileDecoder) Format() string { return "json" }
+
+func (jsonFileDecoder) DecodeFile(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]interface{}{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+type yamlFileDecoder struct{}
+
+func (yamlFileDecoder) Format() string { return "yaml" }
+
+func (yamlFileDecoder) DecodeFile(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+type tomlFileDecoder struct{}
+
+func (tomlFileDecoder) Format() string { return "toml" }
+
+func (tomlFileDecoder) DecodeFile(path string) (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+	if _, err := toml.DecodeFile(path, &values); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// dotenvFileDecoder decodes simple KEY=VALUE files, one assignment per
+// line, with blank lines and "#" prefixed comments ignored.
+type dotenvFileDecoder struct{}
+
+func (dotenvFileDecoder) Format() string { return "env" }
+
+func (dotenvFileDecoder) DecodeFile(path string) (map[string]interface{}, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	values := map[string]interface{}{}
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		key := strings.TrimSpace(parts[0])
+		value := strings.Trim(strings.TrimSpace(parts[1]), `"'`)
+		values[key] = value
+	}
+
+	return values, scanner.Err()
+}
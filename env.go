@@ -0,0 +1,20 @@
+package configurator
+
+// applyEnvSettings configures the internal Viper instance with EnvPrefix,
+// EnvKeyReplacer, and AutomaticEnv, so that the environment variable names
+// Viper derives automatically from field paths when AutomaticEnv is on take
+// EnvPrefix/EnvKeyReplacer into account. Names given explicitly in an env
+// tag are bound as written and are not prefixed.
+func (c *Config) applyEnvSettings() {
+	if c.EnvPrefix != "" {
+		c.viper.SetEnvPrefix(c.EnvPrefix)
+	}
+
+	if c.EnvKeyReplacer != nil {
+		c.viper.SetEnvKeyReplacer(c.EnvKeyReplacer)
+	}
+
+	if c.AutomaticEnv {
+		c.viper.AutomaticEnv()
+	}
+}
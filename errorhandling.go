@@ -0,0 +1,116 @@
+package configurator
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/pflag"
+)
+
+// ErrorHandling controls how Config reacts to flag-parsing errors and
+// --help/-h requests, mirroring pflag.ErrorHandling. Load parses os.Args
+// against the bound FlagSet, so both a bad flag value and a --help/-h
+// request are reachable through real command-line use, not just through
+// values set programmatically.
+type ErrorHandling = pflag.ErrorHandling
+
+// ErrorHandling modes, aliased from pflag so Config.ErrorHandling can be
+// passed straight through to pflag.NewFlagSet.
+const (
+	ContinueOnError = pflag.ContinueOnError
+	ExitOnError     = pflag.ExitOnError
+	PanicOnError    = pflag.PanicOnError
+)
+
+// ErrHelpRequested is returned by Load when the help/h flag was passed and
+// Config.ErrorHandling is ContinueOnError.
+var ErrHelpRequested = errors.New("configurator: help requested")
+
+// fieldMeta captures everything configurator knows about a single struct
+// field from its tags, so PrintDefaults can describe the full schema.
+type fieldMeta struct {
+	path        string
+	flag        string
+	env         string
+	file        string
+	defaultVal  string
+	description string
+}
+
+// buildFieldMeta merges the per-tag parsedValue maps into one fieldMeta per
+// field path, for use by PrintDefaults.
+func buildFieldMeta(defaultValues, envValues, flagValues, configValues, descriptions map[string]parsedValue) []fieldMeta {
+	paths := map[string]struct{}{}
+	for _, values := range []map[string]parsedValue{defaultValues, envValues, flagValues, configValues, descriptions} {
+		for path := range values {
+			paths[path] = struct{}{}
+		}
+	}
+
+	metas := make([]fieldMeta, 0, len(paths))
+	for path := range paths {
+		metas = append(metas, fieldMeta{
+			path:        path,
+			flag:        flagValues[path].tagValue,
+			env:         envValues[path].tagValue,
+			file:        configValues[path].tagValue,
+			defaultVal:  defaultValues[path].tagValue,
+			description: descriptions[path].tagValue,
+		})
+	}
+
+	sort.Slice(metas, func(i, j int) bool { return metas[i].path < metas[j].path })
+
+	return metas
+}
+
+// PrintDefaults writes every bound flag's name, description, env var name,
+// file key, and default value to stdout: one "--name" line per flag,
+// followed by an indented line for each of those that is set. It powers the
+// automatic --help/-h output, but can also be called directly to print the
+// effective configuration schema.
+func (c *Config) PrintDefaults() {
+	for _, f := range c.state().fields {
+		if f.flag == "" {
+			continue
+		}
+
+		fmt.Printf("  --%s\n", f.flag)
+		if f.description != "" {
+			fmt.Printf("\t%s\n", f.description)
+		}
+		if f.env != "" {
+			fmt.Printf("\tenv: %s\n", f.env)
+		}
+		if f.file != "" {
+			fmt.Printf("\tfile: %s\n", f.file)
+		}
+		if f.defaultVal != "" {
+			fmt.Printf("\tdefault: %s\n", f.defaultVal)
+		}
+	}
+}
+
+// handleHelp inspects the help/h flag registered by bindFlagValues and, if
+// it was requested, prints the configuration schema and reports
+// ErrHelpRequested -- or exits/panics instead, according to
+// Config.ErrorHandling.
+func (c *Config) handleHelp(flagSet *pflag.FlagSet) error {
+	help := flagSet.Lookup("help")
+	if help == nil || !help.Changed {
+		return nil
+	}
+
+	c.PrintDefaults()
+
+	switch c.ErrorHandling {
+	case ExitOnError:
+		os.Exit(0)
+	case PanicOnError:
+		panic(ErrHelpRequested)
+	}
+
+	return ErrHelpRequested
+}
@@ -0,0 +1,170 @@
+package configurator
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	timeType     = reflect.TypeOf(time.Time{})
+)
+
+// populateSlice populates a []string or []int field from configValue, which
+// may be a native slice (decoded from a YAML/JSON/TOML file) or a
+// comma-separated string (an env var, flag, or default tag value).
+func populateSlice(field reflect.StructField, fieldValue reflect.Value, configValue interface{}) error {
+	if !fieldValue.IsZero() {
+		return nil
+	}
+
+	items, err := toStringSlice(configValue)
+	if err != nil {
+		return fmt.Errorf("Unable to convert value (%v) to slice for field: %s! Error: %s", configValue, field.Name, err.Error())
+	}
+
+	elemType := field.Type.Elem()
+	slice := reflect.MakeSlice(field.Type, len(items), len(items))
+
+	for i, item := range items {
+		switch elemType.Kind() {
+		case reflect.String:
+			slice.Index(i).SetString(item)
+		case reflect.Int, reflect.Int8, reflect.Int32, reflect.Int64:
+			intValue, err := strconv.ParseInt(item, 10, 64)
+			if err != nil {
+				return fmt.Errorf("Unable to convert value (%s) to int for field: %s! Error: %s", item, field.Name, err.Error())
+			}
+			slice.Index(i).SetInt(intValue)
+		default:
+			return fmt.Errorf("Unsupported slice element type for field: %s!", field.Name)
+		}
+	}
+
+	fieldValue.Set(slice)
+	return nil
+}
+
+func toStringSlice(configValue interface{}) ([]string, error) {
+	switch v := configValue.(type) {
+	case []string:
+		return v, nil
+	case []interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = fmt.Sprintf("%v", item)
+		}
+		return items, nil
+	case string:
+		if v == "" {
+			return nil, nil
+		}
+		return strings.Split(v, ","), nil
+	default:
+		return nil, fmt.Errorf("value is not a slice or comma-separated string")
+	}
+}
+
+// populateMap populates a map[string]string field from configValue, which
+// may be a native map (decoded from a YAML/JSON/TOML file) or a
+// comma-separated "key=value" string (an env var, flag, or default tag
+// value).
+func populateMap(field reflect.StructField, fieldValue reflect.Value, configValue interface{}) error {
+	if !fieldValue.IsZero() {
+		return nil
+	}
+
+	if field.Type.Key().Kind() != reflect.String || field.Type.Elem().Kind() != reflect.String {
+		return fmt.Errorf("Unsupported map type for field: %s! Only map[string]string is supported", field.Name)
+	}
+
+	pairs, err := toStringMap(configValue)
+	if err != nil {
+		return fmt.Errorf("Unable to convert value (%v) to map for field: %s! Error: %s", configValue, field.Name, err.Error())
+	}
+
+	result := reflect.MakeMapWithSize(field.Type, len(pairs))
+	for k, v := range pairs {
+		result.SetMapIndex(reflect.ValueOf(k), reflect.ValueOf(v))
+	}
+
+	fieldValue.Set(result)
+	return nil
+}
+
+func toStringMap(configValue interface{}) (map[string]string, error) {
+	switch v := configValue.(type) {
+	case map[string]string:
+		return v, nil
+	case map[string]interface{}:
+		pairs := make(map[string]string, len(v))
+		for k, item := range v {
+			pairs[k] = fmt.Sprintf("%v", item)
+		}
+		return pairs, nil
+	case string:
+		pairs := map[string]string{}
+		if v == "" {
+			return pairs, nil
+		}
+		for _, kv := range strings.Split(v, ",") {
+			parts := strings.SplitN(kv, "=", 2)
+			if len(parts) != 2 {
+				return nil, fmt.Errorf("expected key=value pairs separated by commas")
+			}
+			pairs[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+		}
+		return pairs, nil
+	default:
+		return nil, fmt.Errorf("value is not a map or comma-separated key=value string")
+	}
+}
+
+// populateDuration populates a time.Duration field using time.ParseDuration,
+// e.g. a default/env/flag/file value of "5s" or "1h30m".
+func populateDuration(field reflect.StructField, fieldValue reflect.Value, configValue interface{}) error {
+	if !fieldValue.IsZero() {
+		return nil
+	}
+
+	switch v := configValue.(type) {
+	case time.Duration:
+		fieldValue.SetInt(int64(v))
+		return nil
+	case string:
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("Unable to convert value (%s) to duration for field: %s! Error: %s", v, field.Name, err.Error())
+		}
+		fieldValue.SetInt(int64(d))
+		return nil
+	default:
+		return fmt.Errorf("Unable to convert value (%v) to duration for field: %s!", configValue, field.Name)
+	}
+}
+
+// populateTime populates a time.Time field, parsing string values as
+// RFC3339 timestamps.
+func populateTime(field reflect.StructField, fieldValue reflect.Value, configValue interface{}) error {
+	if !fieldValue.IsZero() {
+		return nil
+	}
+
+	switch v := configValue.(type) {
+	case time.Time:
+		fieldValue.Set(reflect.ValueOf(v))
+		return nil
+	case string:
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return fmt.Errorf("Unable to convert value (%s) to time for field: %s! Error: %s", v, field.Name, err.Error())
+		}
+		fieldValue.Set(reflect.ValueOf(t))
+		return nil
+	default:
+		return fmt.Errorf("Unable to convert value (%v) to time for field: %s!", configValue, field.Name)
+	}
+}
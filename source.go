@@ -0,0 +1,162 @@
+package configurator
+
+import (
+	"go/ast"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// Source identifies which configuration layer supplied a field's final
+// value, using the same precedence Config documents: overrides beat flags,
+// flags beat environment variables, environment variables beat the
+// configuration file, and the configuration file beats defaults.
+type Source string
+
+// Source values, highest precedence first. SourceUnset means nothing
+// supplied a value and the field was left at its zero value.
+const (
+	SourceOverride Source = "override"
+	SourceFlag     Source = "flag"
+	SourceEnv      Source = "env"
+	SourceFile     Source = "file"
+	SourceDefault  Source = "default"
+	SourceUnset    Source = "unset"
+)
+
+// Source reports which source supplied fieldPath's final value, e.g.
+// c.Source("DB.Host"). fieldPath follows the same dotted convention as the
+// validate/default/env/flag/file tags. Returns SourceUnset if fieldPath is
+// unknown to Config or was never populated.
+func (c *Config) Source(fieldPath string) Source {
+	if src, ok := c.state().sources[fieldPath]; ok {
+		return src
+	}
+	return SourceUnset
+}
+
+// Sources returns the source of every field Config knows about, keyed by
+// its dotted field path. Log or serve this at startup -- e.g. behind your
+// own debug endpoint, the same way Mattermost's environment-config
+// endpoint does -- to see the effective configuration and where each value
+// came from.
+func (c *Config) Sources() map[string]Source {
+	sources := make(map[string]Source, len(c.state().sources))
+	for path, src := range c.state().sources {
+		sources[path] = src
+	}
+	return sources
+}
+
+// snapshotOverrides walks structRef the same way populateStructFields does,
+// recording which leaf fields already hold a non-zero value before
+// population runs. populateStructField never touches a field that's
+// already non-zero (see isZeroOfUnderlyingType), so a field found non-zero
+// here was supplied as an override regardless of what any other source
+// would otherwise have provided.
+//
+// This can't detect a deliberate zero-value override -- a Port field
+// pre-set to 0 looks identical, by reflection, to a Port field nothing
+// ever touched. Callers relying on Config.Source or `validate:"required"`
+// for a field like that need to list its path in Config.Overrides, which
+// parseStructConfigValues merges into the map this function builds.
+func snapshotOverrides(structRef reflect.Value, parentPath string, overrides map[string]bool) {
+	structType := structRef.Type()
+	for i := 0; i < structType.NumField(); i++ {
+		structField := structType.Field(i)
+		if structField.Type == configType || !ast.IsExported(structField.Name) {
+			continue
+		}
+
+		fieldValue := structRef.Field(i)
+		path := fieldPath(parentPath, structField.Name)
+
+		if isNestedStruct(structField.Type) {
+			snapshotOverrides(fieldValue, path, overrides)
+			continue
+		}
+
+		overrides[path] = !fieldValue.IsZero()
+	}
+}
+
+// computeSources determines, for every field path configurator knows
+// about, which source actually supplied its final value. flagSet is the
+// private FlagSet bindFlagValues built for this Load call.
+func (c *Config) computeSources(flagSet *pflag.FlagSet, defaultValues, envValues, flagValues, configValues map[string]parsedValue, overrides map[string]bool) map[string]Source {
+	paths := map[string]struct{}{}
+	for path := range overrides {
+		paths[path] = struct{}{}
+	}
+	for _, values := range []map[string]parsedValue{defaultValues, envValues, flagValues, configValues} {
+		for path := range values {
+			paths[path] = struct{}{}
+		}
+	}
+
+	sources := make(map[string]Source, len(paths))
+	for path := range paths {
+		sources[path] = c.fieldSource(flagSet, path, defaultValues, envValues, flagValues, configValues, overrides)
+	}
+
+	return sources
+}
+
+// fieldSource determines a single field's source, checking each precedence
+// level in turn: override, then the flag's Changed state, then whether the
+// bound (explicit or AutomaticEnv-derived) environment variable is set,
+// then whether viper found the key in the decoded config file, and
+// finally whether a default was registered for it.
+func (c *Config) fieldSource(flagSet *pflag.FlagSet, path string, defaultValues, envValues, flagValues, configValues map[string]parsedValue, overrides map[string]bool) Source {
+	if overrides[path] {
+		return SourceOverride
+	}
+
+	if flagValue, ok := flagValues[path]; ok {
+		if flag := flagSet.Lookup(flagValue.tagValue); flag != nil && flag.Changed {
+			return SourceFlag
+		}
+	}
+
+	if envValue, ok := envValues[path]; ok {
+		if _, set := os.LookupEnv(envValue.tagValue); set {
+			return SourceEnv
+		}
+	}
+
+	if c.AutomaticEnv {
+		if _, set := os.LookupEnv(c.automaticEnvKey(path)); set {
+			return SourceEnv
+		}
+	}
+
+	if c.viper.InConfig(path) {
+		return SourceFile
+	}
+
+	if _, ok := defaultValues[path]; ok {
+		return SourceDefault
+	}
+
+	return SourceUnset
+}
+
+// automaticEnvKey derives the environment variable name AutomaticEnv
+// resolves path from, mirroring Viper's own prefix/uppercase/replacer
+// handling so source detection matches what actually got populated (see
+// Config.AutomaticEnv).
+func (c *Config) automaticEnvKey(path string) string {
+	key := path
+	if c.EnvPrefix != "" {
+		key = c.EnvPrefix + "_" + key
+	}
+	key = strings.ToUpper(key)
+
+	if c.EnvKeyReplacer != nil {
+		key = c.EnvKeyReplacer.Replace(key)
+	}
+
+	return key
+}
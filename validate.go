@@ -0,0 +1,162 @@
+package configurator
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Validator is implemented by configuration structs that need additional
+// validation beyond what `validate` tags can express. If structRef passed
+// to Config.Load implements Validator, Load calls Validate() after the
+// struct has been populated and its tag-based validation has passed.
+type Validator interface {
+	Validate() error
+}
+
+// ValidationError aggregates every validation failure found while checking
+// a struct against its `validate` tags, so callers see every bad field
+// instead of just the first one.
+type ValidationError struct {
+	Errors []error
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return fmt.Sprintf("configurator: validation failed: %s", strings.Join(messages, "; "))
+}
+
+type validationRule struct {
+	name  string
+	param string
+}
+
+// parseValidationRules splits a `validate:"required,min=1,max=65535,oneof=dev staging prod"`
+// tag value into its individual rules.
+func parseValidationRules(tagValue string) []validationRule {
+	parts := strings.Split(tagValue, ",")
+	rules := make([]validationRule, 0, len(parts))
+
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		if idx := strings.Index(part, "="); idx != -1 {
+			rules = append(rules, validationRule{name: part[:idx], param: part[idx+1:]})
+		} else {
+			rules = append(rules, validationRule{name: part})
+		}
+	}
+
+	return rules
+}
+
+// validateStruct checks structRef against its `validate` tags, recursing
+// into nested structs the same way parseValuesForTag does, and returns a
+// ValidationError aggregating every failure found.
+func (c *Config) validateStruct(structRef reflect.Value) error {
+	rules := parseValuesForTag(structRef, tagValidate)
+
+	var errs []error
+	for path, parsed := range rules {
+		fieldValue := fieldValueAtPath(structRef, path)
+
+		for _, rule := range parseValidationRules(parsed.tagValue) {
+			if err := c.applyValidationRule(path, fieldValue, rule); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return &ValidationError{Errors: errs}
+}
+
+func (c *Config) applyValidationRule(path string, fieldValue reflect.Value, rule validationRule) error {
+	switch rule.name {
+	case "required":
+		// Source reflects every precedence level, including an override
+		// supplied directly on the struct before Load, so a legitimately
+		// zero value still passes as long as some source actually produced
+		// it -- only a field nothing ever touched reports SourceUnset. A
+		// zero-value override needs its path listed in Config.Overrides to
+		// be seen at all; see snapshotOverrides.
+		if c.Source(path) == SourceUnset {
+			return fmt.Errorf("field %s is required", path)
+		}
+
+	case "min":
+		return validateBound(path, fieldValue, rule.param, "min")
+
+	case "max":
+		return validateBound(path, fieldValue, rule.param, "max")
+
+	case "oneof":
+		return validateOneOf(path, fieldValue, rule.param)
+	}
+
+	return nil
+}
+
+// fieldValueAtPath navigates a dotted field path (as produced by
+// collectValuesForTag) back to its reflect.Value, e.g. "DB.Host" resolves
+// to structRef.FieldByName("DB").FieldByName("Host").
+func fieldValueAtPath(structRef reflect.Value, path string) reflect.Value {
+	current := structRef
+	for _, part := range strings.Split(path, ".") {
+		current = current.FieldByName(part)
+	}
+	return current
+}
+
+func validateBound(path string, fieldValue reflect.Value, param, kind string) error {
+	bound, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return fmt.Errorf("field %s has an invalid %s bound (%s)", path, kind, param)
+	}
+
+	var actual float64
+	switch fieldValue.Kind() {
+	case reflect.String:
+		actual = float64(len(fieldValue.String()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		actual = float64(fieldValue.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		actual = float64(fieldValue.Uint())
+	case reflect.Float32, reflect.Float64:
+		actual = fieldValue.Float()
+	default:
+		return fmt.Errorf("field %s does not support %s validation", path, kind)
+	}
+
+	if kind == "min" && actual < bound {
+		return fmt.Errorf("field %s must be >= %s", path, param)
+	}
+	if kind == "max" && actual > bound {
+		return fmt.Errorf("field %s must be <= %s", path, param)
+	}
+
+	return nil
+}
+
+func validateOneOf(path string, fieldValue reflect.Value, param string) error {
+	allowed := strings.Fields(param)
+	value := fmt.Sprintf("%v", fieldValue.Interface())
+
+	for _, a := range allowed {
+		if a == value {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("field %s must be one of [%s], got %q", path, strings.Join(allowed, " "), value)
+}
@@ -0,0 +1,129 @@
+package configurator
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounceInterval is the window within which rapid successive
+// filesystem events for the same config file are coalesced into a single
+// reload, since editors and sync tools often emit several writes in a row
+// for what is conceptually one change.
+const watchDebounceInterval = 200 * time.Millisecond
+
+// ErrNotLoaded is returned by Watch when it is called on a Config that has
+// not yet had Load called on it, since Watch relies on the Viper instance
+// and file settings established by Load.
+var ErrNotLoaded = errors.New("Watch requires Load to be called first!")
+
+// Watch begins watching the configuration file backing structRef for
+// changes and keeps structRef up to date as the file changes on disk.
+// structRef must be the same struct pointer previously passed to Load.
+//
+// On each change, Watch parses a fresh copy of the struct using the same
+// defaults/env/flag/file precedence as Load, then atomically swaps
+// structRef's fields with the fresh values under a mutex and invokes
+// onChange with the old and new snapshots. If onChange returns an error the
+// swap is rolled back and structRef is left untouched.
+//
+// The returned stop function cancels any pending reload and makes Watch
+// ignore every later change to the file. It does not close the underlying
+// Viper file watcher, which keeps running for the life of the process.
+func (c *Config) Watch(structRef interface{}, onChange func(old, new interface{}) error) (stop func(), err error) {
+	if err := c.canLoad(structRef); err != nil {
+		return nil, err
+	}
+	if c.viper == nil {
+		return nil, ErrNotLoaded
+	}
+
+	ptrRef := reflect.ValueOf(structRef)
+	structType := ptrRef.Elem().Type()
+
+	var debounceMu sync.Mutex
+	var debounce *time.Timer
+	stopped := false
+
+	c.viper.OnConfigChange(func(_ fsnotify.Event) {
+		debounceMu.Lock()
+		defer debounceMu.Unlock()
+
+		if stopped {
+			return
+		}
+		if debounce != nil {
+			debounce.Stop()
+		}
+		debounce = time.AfterFunc(watchDebounceInterval, func() {
+			c.reload(structType, ptrRef.Elem(), onChange)
+		})
+	})
+	c.viper.WatchConfig()
+
+	stop = func() {
+		debounceMu.Lock()
+		defer debounceMu.Unlock()
+
+		stopped = true
+		if debounce != nil {
+			debounce.Stop()
+		}
+	}
+
+	return stop, nil
+}
+
+// reload parses a fresh copy of structType using the same source precedence
+// as Load, then swaps liveRef's fields to the fresh values and invokes
+// onChange with the old and new snapshots, rolling back on error.
+func (c *Config) reload(structType reflect.Type, liveRef reflect.Value, onChange func(old, new interface{}) error) {
+	fresh := reflect.New(structType)
+
+	freshConfig := Config{
+		FileName:       c.FileName,
+		FilePaths:      c.FilePaths,
+		FileDecoders:   c.FileDecoders,
+		ErrorHandling:  c.ErrorHandling,
+		EnvPrefix:      c.EnvPrefix,
+		EnvKeyReplacer: c.EnvKeyReplacer,
+		AutomaticEnv:   c.AutomaticEnv,
+	}
+	if err := freshConfig.Load(fresh.Interface()); err != nil {
+		return
+	}
+
+	c.state().mu.Lock()
+	defer c.state().mu.Unlock()
+
+	old := reflect.New(structType)
+	copyNonConfigFields(old.Elem(), liveRef)
+
+	copyNonConfigFields(liveRef, fresh.Elem())
+
+	if onChange == nil {
+		return
+	}
+
+	if err := onChange(old.Elem().Interface(), fresh.Elem().Interface()); err != nil {
+		copyNonConfigFields(liveRef, old.Elem())
+	}
+}
+
+// configType is used to identify and skip the embedded Config field when
+// copying values between struct snapshots, since FileName/FilePaths/viper
+// belong to the live Config and must never be clobbered by a reload.
+var configType = reflect.TypeOf(Config{})
+
+func copyNonConfigFields(dst, src reflect.Value) {
+	structType := src.Type()
+	for i := 0; i < structType.NumField(); i++ {
+		if structType.Field(i).Type == configType {
+			continue
+		}
+		dst.Field(i).Set(src.Field(i))
+	}
+}